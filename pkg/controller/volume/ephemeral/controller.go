@@ -18,6 +18,8 @@ package ephemeral
 
 import (
 	"context"
+	"encoding/json"
+	goerrors "errors"
 	"fmt"
 	"time"
 
@@ -26,13 +28,18 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
 	coreinformers "k8s.io/client-go/informers/core/v1"
+	storageinformers "k8s.io/client-go/informers/storage/v1"
 	clientset "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
 	v1core "k8s.io/client-go/kubernetes/typed/core/v1"
 	corelisters "k8s.io/client-go/listers/core/v1"
+	storagelisters "k8s.io/client-go/listers/storage/v1"
 	"k8s.io/client-go/tools/cache"
 	kcache "k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -41,11 +48,48 @@ import (
 	"k8s.io/kubernetes/pkg/controller/volume/common"
 	ephemeralvolumemetrics "k8s.io/kubernetes/pkg/controller/volume/ephemeral/metrics"
 	"k8s.io/kubernetes/pkg/controller/volume/events"
+	"k8s.io/kubernetes/pkg/features"
+	gatewayinformers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1beta1"
+	gatewaylisters "sigs.k8s.io/gateway-api/pkg/client/listers/apis/v1beta1"
 )
 
 // Controller creates PVCs for ephemeral inline volumes in a pod spec.
 type Controller interface {
-	Run(workers int, stopCh <-chan struct{})
+	Run(ctx context.Context, workers int)
+}
+
+// RetentionPolicy controls what happens to the PVC generated for an
+// ephemeral inline volume once its pod is deleted.
+type RetentionPolicy string
+
+const (
+	// RetentionPolicyDelete is the default: the PVC is garbage-collected
+	// together with its pod.
+	RetentionPolicyDelete RetentionPolicy = "Delete"
+	// RetentionPolicyRetain keeps the PVC, and the PV it is bound to,
+	// around after the pod that created it is gone.
+	RetentionPolicyRetain RetentionPolicy = "Retain"
+
+	// AnnRetentionPolicy is a well-known annotation on the
+	// VolumeClaimTemplate that selects the RetentionPolicy for the
+	// generated PVC. Any value other than RetentionPolicyRetain is
+	// treated as RetentionPolicyDelete.
+	AnnRetentionPolicy = "volume.kubernetes.io/ephemeral-pvc-retention-policy"
+
+	// annPodUID records the UID of the pod a PVC was generated for. It is
+	// used instead of relying solely on the owner reference so that
+	// ownership can still be verified for retained PVCs, which
+	// deliberately have no owner reference to their pod.
+	annPodUID = "volume.kubernetes.io/ephemeral-pod-uid"
+)
+
+// retentionPolicy returns the RetentionPolicy requested for vol, defaulting
+// to RetentionPolicyDelete when unset or unrecognized.
+func retentionPolicy(vol *v1.Volume) RetentionPolicy {
+	if policy := vol.Ephemeral.VolumeClaimTemplate.Annotations[AnnRetentionPolicy]; policy == string(RetentionPolicyRetain) {
+		return RetentionPolicyRetain
+	}
+	return RetentionPolicyDelete
 }
 
 type ephemeralController struct {
@@ -69,6 +113,18 @@ type ephemeralController struct {
 	// limit iteration over pods to those of interest.
 	podIndexer cache.Indexer
 
+	// referenceGrantLister is the shared ReferenceGrant lister used to
+	// authorize cross-namespace DataSourceRef lookups. It is only
+	// populated when the CrossNamespaceVolumeDataSource feature gate is
+	// enabled.
+	referenceGrantLister  gatewaylisters.ReferenceGrantLister
+	referenceGrantsSynced kcache.InformerSynced
+
+	// storageClassLister is used to check whether an existing PVC's
+	// StorageClass supports volume expansion before growing it.
+	storageClassLister   storagelisters.StorageClassLister
+	storageClassesSynced kcache.InformerSynced
+
 	// recorder is used to record events in the API server
 	recorder record.EventRecorder
 
@@ -79,16 +135,20 @@ type ephemeralController struct {
 func NewController(
 	kubeClient clientset.Interface,
 	podInformer coreinformers.PodInformer,
-	pvcInformer coreinformers.PersistentVolumeClaimInformer) (Controller, error) {
+	pvcInformer coreinformers.PersistentVolumeClaimInformer,
+	referenceGrantInformer gatewayinformers.ReferenceGrantInformer,
+	storageClassInformer storageinformers.StorageClassInformer) (Controller, error) {
 
 	ec := &ephemeralController{
-		kubeClient: kubeClient,
-		podLister:  podInformer.Lister(),
-		podIndexer: podInformer.Informer().GetIndexer(),
-		podSynced:  podInformer.Informer().HasSynced,
-		pvcLister:  pvcInformer.Lister(),
-		pvcsSynced: pvcInformer.Informer().HasSynced,
-		queue:      workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ephemeral_volume"),
+		kubeClient:           kubeClient,
+		podLister:            podInformer.Lister(),
+		podIndexer:           podInformer.Informer().GetIndexer(),
+		podSynced:            podInformer.Informer().HasSynced,
+		pvcLister:            pvcInformer.Lister(),
+		pvcsSynced:           pvcInformer.Informer().HasSynced,
+		storageClassLister:   storageClassInformer.Lister(),
+		storageClassesSynced: storageClassInformer.Informer().HasSynced,
+		queue:                workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "ephemeral_volume"),
 	}
 
 	ephemeralvolumemetrics.RegisterMetrics()
@@ -103,8 +163,11 @@ func NewController(
 		// The pod spec is immutable. Therefore the controller can ignore pod updates
 		// because there cannot be any changes that have to be copied into the generated
 		// PVC.
-		// Deletion of the PVC is handled through the owner reference and garbage collection.
-		// Therefore pod deletions also can be ignored.
+		// Deletion of a PVC generated with RetentionPolicyDelete is handled
+		// through the owner reference and garbage collection. Retained PVCs
+		// have no such owner reference, so onPodDelete only records metrics
+		// and leaves them alone.
+		DeleteFunc: ec.onPodDelete,
 	})
 	pvcInformer.Informer().AddEventHandler(kcache.ResourceEventHandlerFuncs{
 		DeleteFunc: ec.onPVCDelete,
@@ -113,6 +176,11 @@ func NewController(
 		return nil, fmt.Errorf("could not initialize pvc protection controller: %w", err)
 	}
 
+	if utilfeature.DefaultFeatureGate.Enabled(features.CrossNamespaceVolumeDataSource) {
+		ec.referenceGrantLister = referenceGrantInformer.Lister()
+		ec.referenceGrantsSynced = referenceGrantInformer.Informer().HasSynced
+	}
+
 	return ec, nil
 }
 
@@ -148,7 +216,7 @@ func (ec *ephemeralController) onPVCDelete(obj interface{}) {
 	}
 
 	// Someone deleted a PVC, either intentionally or
-	// accidentally. If there is a pod referencing it because of
+	// accidentally. If there is a live pod referencing it because of
 	// an ephemeral volume, then we should re-create the PVC.
 	// The common indexer does some prefiltering for us by
 	// limiting the list to those pods which reference
@@ -159,41 +227,97 @@ func (ec *ephemeralController) onPVCDelete(obj interface{}) {
 		return
 	}
 	for _, obj := range objs {
+		pod, ok := obj.(*v1.Pod)
+		if !ok {
+			continue
+		}
+		if pod.DeletionTimestamp != nil && pvc.Annotations[AnnRetentionPolicy] == string(RetentionPolicyRetain) {
+			// The pod is on its way out and this PVC was deliberately
+			// retained: do not recreate it on the pod's behalf.
+			continue
+		}
 		ec.enqueuePod(obj)
 	}
 }
 
-func (ec *ephemeralController) Run(workers int, stopCh <-chan struct{}) {
+// onPodDelete is invoked when a pod is removed from the informer cache. Its
+// ephemeral volumes whose PVCs were created with RetentionPolicyDelete are
+// removed by the garbage collector through the owner reference already set
+// on the PVC; this handler only accounts for the outcome in metrics.
+// Retained PVCs have no owner reference to the pod and are therefore left
+// untouched.
+func (ec *ephemeralController) onPodDelete(obj interface{}) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		tombstone, ok := obj.(kcache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		pod, ok = tombstone.Obj.(*v1.Pod)
+		if !ok {
+			return
+		}
+	}
+
+	for _, vol := range pod.Spec.Volumes {
+		if vol.Ephemeral == nil {
+			continue
+		}
+		if retentionPolicy(&vol) == RetentionPolicyRetain {
+			klog.V(5).InfoS("Ephemeral: pod deleted, retaining PVC", "pod", klog.KObj(pod), "volumeName", vol.Name)
+			ephemeralvolumemetrics.EphemeralVolumeRetainedOnPodDeletion.Inc()
+		} else {
+			ephemeralvolumemetrics.EphemeralVolumeDeletedOnPodDeletion.Inc()
+		}
+	}
+}
+
+// Run starts the controller's workers and blocks until ctx is done.
+//
+// TODO(cmd/kube-controller-manager): that command is out of scope for this
+// checkout and still starts this controller from a stopCh; its call site
+// needs a matching update to pass a context.Context (e.g. derived via
+// wait.ContextForChannel) once this change lands.
+func (ec *ephemeralController) Run(ctx context.Context, workers int) {
 	defer runtime.HandleCrash()
 	defer ec.queue.ShutDown()
 
-	klog.Infof("Starting ephemeral volume controller")
-	defer klog.Infof("Shutting down ephemeral volume controller")
-
-	if !cache.WaitForNamedCacheSync("ephemeral", stopCh, ec.podSynced, ec.pvcsSynced) {
+	logger := klog.FromContext(ctx)
+	logger.Info("Starting ephemeral volume controller")
+	defer logger.Info("Shutting down ephemeral volume controller")
+
+	// The referenceGrantLister's cache is deliberately not part of this
+	// barrier: ReferenceGrant is a gateway-api CRD that may not be
+	// installed in the cluster at all, and an uninstalled CRD's informer
+	// never reports synced. Waiting on it here would hang the entire
+	// ephemeral-volume controller at startup even for clusters that
+	// never use a cross-namespace DataSourceRef. Cross-namespace lookups
+	// that race the informer's initial list simply fail closed and get
+	// requeued with backoff until it catches up.
+	if !cache.WaitForNamedCacheSync("ephemeral", ctx.Done(), ec.podSynced, ec.pvcsSynced, ec.storageClassesSynced) {
 		return
 	}
 
 	for i := 0; i < workers; i++ {
-		go wait.Until(ec.runWorker, time.Second, stopCh)
+		go wait.UntilWithContext(ctx, ec.runWorker, time.Second)
 	}
 
-	<-stopCh
+	<-ctx.Done()
 }
 
-func (ec *ephemeralController) runWorker() {
-	for ec.processNextWorkItem() {
+func (ec *ephemeralController) runWorker(ctx context.Context) {
+	for ec.processNextWorkItem(ctx) {
 	}
 }
 
-func (ec *ephemeralController) processNextWorkItem() bool {
+func (ec *ephemeralController) processNextWorkItem(ctx context.Context) bool {
 	key, shutdown := ec.queue.Get()
 	if shutdown {
 		return false
 	}
 	defer ec.queue.Done(key)
 
-	err := ec.syncHandler(key.(string))
+	err := ec.syncHandler(ctx, key.(string))
 	if err == nil {
 		ec.queue.Forget(key)
 		return true
@@ -207,7 +331,8 @@ func (ec *ephemeralController) processNextWorkItem() bool {
 
 // syncHandler is invoked for each pod which might need to be processed.
 // If an error is returned from this function, the pod will be requeued.
-func (ec *ephemeralController) syncHandler(key string) error {
+func (ec *ephemeralController) syncHandler(ctx context.Context, key string) error {
+	logger := klog.FromContext(ctx)
 	namespace, name, err := kcache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		return err
@@ -215,22 +340,27 @@ func (ec *ephemeralController) syncHandler(key string) error {
 	pod, err := ec.podLister.Pods(namespace).Get(name)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			klog.V(5).Infof("ephemeral: nothing to do for pod %s, it is gone", key)
+			logger.V(5).Info("Ephemeral: nothing to do for pod, it is gone", "pod", klog.KRef(namespace, name))
 			return nil
 		}
-		klog.V(5).Infof("Error getting pod %s/%s (uid: %q) from informer : %v", pod.Namespace, pod.Name, pod.UID, err)
+		logger.V(5).Info("Ephemeral: error getting pod from informer", "pod", klog.KRef(namespace, name), "err", err)
 		return err
 	}
 
 	// Ignore pods which are already getting deleted.
 	if pod.DeletionTimestamp != nil {
-		klog.V(5).Infof("ephemeral: nothing to do for pod %s, it is marked for deletion", key)
+		logger.V(5).Info("Ephemeral: nothing to do for pod, it is marked for deletion", "pod", klog.KObj(pod))
 		return nil
 	}
 
 	for _, vol := range pod.Spec.Volumes {
-		if err := ec.handleVolume(pod, vol); err != nil {
-			ec.recorder.Event(pod, v1.EventTypeWarning, events.FailedBinding, fmt.Sprintf("ephemeral volume %s: %v", vol.Name, err))
+		if err := ec.handleVolume(ctx, pod, vol); err != nil {
+			reason := events.FailedBinding
+			var missingGrant *missingReferenceGrantError
+			if goerrors.As(err, &missingGrant) {
+				reason = events.MissingReferenceGrant
+			}
+			ec.recorder.Event(pod, v1.EventTypeWarning, reason, fmt.Sprintf("ephemeral volume %s: %v", vol.Name, err))
 			return fmt.Errorf("pod %s, ephemeral volume %s: %v", key, vol.Name, err)
 		}
 	}
@@ -238,9 +368,119 @@ func (ec *ephemeralController) syncHandler(key string) error {
 	return nil
 }
 
+// missingReferenceGrantError indicates that a cross-namespace DataSourceRef
+// could not be authorized because no matching ReferenceGrant was found in
+// the target namespace.
+type missingReferenceGrantError struct {
+	err error
+}
+
+func (e *missingReferenceGrantError) Error() string { return e.err.Error() }
+func (e *missingReferenceGrantError) Unwrap() error { return e.err }
+
+// checkReferenceGrant verifies that a gateway.networking.k8s.io/ReferenceGrant
+// in the DataSourceRef's namespace permits pod.Namespace to reference the
+// given kind/name.
+func (ec *ephemeralController) checkReferenceGrant(pod *v1.Pod, dataSourceRef *v1.TypedObjectReference) error {
+	targetNamespace := *dataSourceRef.Namespace
+	grants, err := ec.referenceGrantLister.ReferenceGrants(targetNamespace).List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("list ReferenceGrants in namespace %s: %v", targetNamespace, err)
+	}
+
+	group := ""
+	if dataSourceRef.APIGroup != nil {
+		group = *dataSourceRef.APIGroup
+	}
+	for _, grant := range grants {
+		for _, from := range grant.Spec.From {
+			// The resource that actually carries the cross-namespace
+			// DataSourceRef is the generated PersistentVolumeClaim, not
+			// the pod, so that is what operators authorize in the
+			// ReferenceGrant's From entries.
+			if string(from.Group) != "" || string(from.Kind) != "PersistentVolumeClaim" || string(from.Namespace) != pod.Namespace {
+				continue
+			}
+			for _, to := range grant.Spec.To {
+				if string(to.Group) != group || string(to.Kind) != dataSourceRef.Kind {
+					continue
+				}
+				if to.Name == nil || string(*to.Name) == dataSourceRef.Name {
+					return nil
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("no ReferenceGrant in namespace %s permits namespace %s to reference %s/%s %q", targetNamespace, pod.Namespace, group, dataSourceRef.Kind, dataSourceRef.Name)
+}
+
+// reconcileSize grows an already-existing ephemeral PVC when the pod's
+// VolumeClaimTemplate now requests more storage than the PVC currently has,
+// provided the PVC's StorageClass allows volume expansion. It never shrinks
+// a PVC: a template that requests less storage than the PVC already has is
+// a no-op, except for a warning event.
+func (ec *ephemeralController) reconcileSize(ctx context.Context, pod *v1.Pod, vol v1.Volume, pvc *v1.PersistentVolumeClaim) error {
+	logger := klog.FromContext(ctx)
+	wantSize, ok := vol.Ephemeral.VolumeClaimTemplate.Spec.Resources.Requests[v1.ResourceStorage]
+	if !ok {
+		return nil
+	}
+	haveSize, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	if !ok {
+		return nil
+	}
+
+	switch wantSize.Cmp(haveSize) {
+	case 0:
+		return nil
+	case -1:
+		ec.recorder.Eventf(pod, v1.EventTypeWarning, "EphemeralVolumeShrinkRejected", "ephemeral volume %s: PVC %s already has size %s, ignoring smaller requested size %s", vol.Name, pvc.Name, haveSize.String(), wantSize.String())
+		return nil
+	}
+
+	if pvc.Spec.StorageClassName == nil {
+		logger.V(5).Info("Ephemeral: PVC has no StorageClassName, cannot check whether expansion is supported", "volumeName", vol.Name, "PVC", klog.KObj(pvc))
+		return nil
+	}
+	storageClass, err := ec.storageClassLister.Get(*pvc.Spec.StorageClassName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("get StorageClass %s: %v", *pvc.Spec.StorageClassName, err)
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		ec.recorder.Eventf(pod, v1.EventTypeWarning, "EphemeralVolumeExpansionUnsupported", "ephemeral volume %s: StorageClass %s does not allow volume expansion, cannot grow PVC %s to %s", vol.Name, *pvc.Spec.StorageClassName, pvc.Name, wantSize.String())
+		return nil
+	}
+
+	ephemeralvolumemetrics.EphemeralVolumeExpansionAttempts.Inc()
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"resources": map[string]interface{}{
+				"requests": map[string]interface{}{
+					string(v1.ResourceStorage): wantSize.String(),
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("marshal resize patch for PVC %s: %v", pvc.Name, err)
+	}
+	_, err = ec.kubeClient.CoreV1().PersistentVolumeClaims(pod.Namespace).Patch(ctx, pvc.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		ephemeralvolumemetrics.EphemeralVolumeExpansionFailures.Inc()
+		ec.recorder.Eventf(pod, v1.EventTypeWarning, "EphemeralVolumeExpansionFailed", "ephemeral volume %s: failed to grow PVC %s to %s: %v", vol.Name, pvc.Name, wantSize.String(), err)
+		return fmt.Errorf("grow PVC %s to %s: %v", pvc.Name, wantSize.String(), err)
+	}
+	return nil
+}
+
 // handleEphemeralVolume is invoked for each volume of a pod.
-func (ec *ephemeralController) handleVolume(pod *v1.Pod, vol v1.Volume) error {
-	klog.V(5).Infof("ephemeral: checking volume %s", vol.Name)
+func (ec *ephemeralController) handleVolume(ctx context.Context, pod *v1.Pod, vol v1.Volume) error {
+	logger := klog.FromContext(ctx)
+	logger.V(5).Info("Ephemeral: checking volume", "volumeName", vol.Name)
 	if vol.Ephemeral == nil {
 		return nil
 	}
@@ -251,36 +491,78 @@ func (ec *ephemeralController) handleVolume(pod *v1.Pod, vol v1.Volume) error {
 		return err
 	}
 	if pvc != nil {
-		if err := ephemeral.VolumeIsForPod(pod, pvc); err != nil {
+		if pvc.Annotations[AnnRetentionPolicy] == string(RetentionPolicyRetain) {
+			// Retained PVCs intentionally have no owner reference to
+			// their pod, so the shared ephemeral.VolumeIsForPod check
+			// (which relies on one) cannot be used for them. Fall back
+			// to the pod UID we stamped onto the PVC when we created it.
+			if uid := pvc.Annotations[annPodUID]; uid != string(pod.UID) {
+				return fmt.Errorf("PVC %s was not created for pod %s/%s (pod UID mismatch)", pvcName, pod.Namespace, pod.Name)
+			}
+		} else if err := ephemeral.VolumeIsForPod(pod, pvc); err != nil {
 			return err
 		}
-		// Already created, nothing more to do.
-		klog.V(5).Infof("ephemeral: volume %s: PVC %s already created", vol.Name, pvcName)
-		return nil
+		// Already created. The only thing left to reconcile is a
+		// request to grow the volume.
+		logger.V(5).Info("Ephemeral: PVC already created", "volumeName", vol.Name, "PVC", klog.KObj(pvc))
+		return ec.reconcileSize(ctx, pod, vol, pvc)
 	}
 
-	// Create the PVC with pod as owner.
-	isTrue := true
+	dataSourceRef := vol.Ephemeral.VolumeClaimTemplate.Spec.DataSourceRef
+	if dataSourceRef != nil && dataSourceRef.Namespace != nil && *dataSourceRef.Namespace != pod.Namespace {
+		if !utilfeature.DefaultFeatureGate.Enabled(features.CrossNamespaceVolumeDataSource) {
+			return fmt.Errorf("volume %s: DataSourceRef may not reference namespace %q unless the CrossNamespaceVolumeDataSource feature gate is enabled", vol.Name, *dataSourceRef.Namespace)
+		}
+		ephemeralvolumemetrics.EphemeralVolumeCrossNamespaceAttempts.Inc()
+		if err := ec.checkReferenceGrant(pod, dataSourceRef); err != nil {
+			ephemeralvolumemetrics.EphemeralVolumeCrossNamespaceDenied.Inc()
+			return &missingReferenceGrantError{err: err}
+		}
+	}
+
+	// Create the PVC with pod as owner, unless retention was requested for
+	// this volume, in which case the PVC must survive the pod's deletion
+	// and therefore gets no owner reference to it. The pod UID is only
+	// stamped onto retained PVCs: it exists solely to let handleVolume
+	// verify ownership of a PVC that, by design, has no owner reference
+	// to check instead.
+	annotations := vol.Ephemeral.VolumeClaimTemplate.Annotations
+	var ownerReferences []metav1.OwnerReference
+	if policy := retentionPolicy(&vol); policy == RetentionPolicyRetain {
+		annotations = make(map[string]string, len(vol.Ephemeral.VolumeClaimTemplate.Annotations)+1)
+		for k, v := range vol.Ephemeral.VolumeClaimTemplate.Annotations {
+			annotations[k] = v
+		}
+		annotations[annPodUID] = string(pod.UID)
+	} else {
+		isTrue := true
+		ownerReferences = []metav1.OwnerReference{
+			{
+				APIVersion:         "v1",
+				Kind:               "Pod",
+				Name:               pod.Name,
+				UID:                pod.UID,
+				Controller:         &isTrue,
+				BlockOwnerDeletion: &isTrue,
+			},
+		}
+	}
 	pvc = &v1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: pvcName,
-			OwnerReferences: []metav1.OwnerReference{
-				{
-					APIVersion:         "v1",
-					Kind:               "Pod",
-					Name:               pod.Name,
-					UID:                pod.UID,
-					Controller:         &isTrue,
-					BlockOwnerDeletion: &isTrue,
-				},
-			},
-			Annotations: vol.Ephemeral.VolumeClaimTemplate.Annotations,
-			Labels:      vol.Ephemeral.VolumeClaimTemplate.Labels,
+			Name:            pvcName,
+			OwnerReferences: ownerReferences,
+			Annotations:     annotations,
+			Labels:          vol.Ephemeral.VolumeClaimTemplate.Labels,
 		},
 		Spec: vol.Ephemeral.VolumeClaimTemplate.Spec,
 	}
+	if dataSourceRef != nil {
+		// Preserve the cross-namespace reference; apiserver validation only
+		// allows this when the DataSourceRef was already set this way.
+		pvc.Spec.DataSourceRef = dataSourceRef.DeepCopy()
+	}
 	ephemeralvolumemetrics.EphemeralVolumeCreateAttempts.Inc()
-	_, err = ec.kubeClient.CoreV1().PersistentVolumeClaims(pod.Namespace).Create(context.TODO(), pvc, metav1.CreateOptions{})
+	_, err = ec.kubeClient.CoreV1().PersistentVolumeClaims(pod.Namespace).Create(ctx, pvc, metav1.CreateOptions{})
 	if err != nil {
 		ephemeralvolumemetrics.EphemeralVolumeCreateFailures.Inc()
 		return fmt.Errorf("create PVC %s: %v", pvcName, err)
@@ -0,0 +1,615 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ephemeral
+
+import (
+	"context"
+	goerrors "errors"
+	"strings"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilfeature "k8s.io/apiserver/pkg/util/feature"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/component-base/metrics/testutil"
+	ephemeralvolumemetrics "k8s.io/kubernetes/pkg/controller/volume/ephemeral/metrics"
+	"k8s.io/kubernetes/pkg/controller/volume/events"
+	"k8s.io/kubernetes/pkg/features"
+	"k8s.io/utils/ptr"
+	gatewayapi "sigs.k8s.io/gateway-api/apis/v1beta1"
+	gatewayfake "sigs.k8s.io/gateway-api/pkg/client/clientset/versioned/fake"
+	gatewayinformers "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
+)
+
+const (
+	testNamespace = "test"
+	testPodName   = "my-pod"
+	testPodUID    = types.UID("uid-1")
+)
+
+func testPod(uid types.UID, volumes ...v1.Volume) *v1.Pod {
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testPodName,
+			Namespace: testNamespace,
+			UID:       uid,
+		},
+		Spec: v1.PodSpec{
+			Volumes: volumes,
+		},
+	}
+}
+
+func ephemeralVolume(name string, retain bool) v1.Volume {
+	annotations := map[string]string{}
+	if retain {
+		annotations[AnnRetentionPolicy] = string(RetentionPolicyRetain)
+	}
+	return v1.Volume{
+		Name: name,
+		VolumeSource: v1.VolumeSource{
+			Ephemeral: &v1.EphemeralVolumeSource{
+				VolumeClaimTemplate: &v1.PersistentVolumeClaimTemplate{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: annotations,
+					},
+				},
+			},
+		},
+	}
+}
+
+func ephemeralVolumeWithSize(name, size string) v1.Volume {
+	vol := ephemeralVolume(name, false)
+	vol.Ephemeral.VolumeClaimTemplate.Spec.Resources = v1.ResourceRequirements{
+		Requests: v1.ResourceList{
+			v1.ResourceStorage: resource.MustParse(size),
+		},
+	}
+	return vol
+}
+
+const sourceNamespace = "source-ns"
+
+func ephemeralVolumeWithDataSourceRef(name, namespace, kind, dataName string) v1.Volume {
+	vol := ephemeralVolume(name, false)
+	vol.Ephemeral.VolumeClaimTemplate.Spec.DataSourceRef = &v1.TypedObjectReference{
+		Kind:      kind,
+		Name:      dataName,
+		Namespace: ptr.To(namespace),
+	}
+	return vol
+}
+
+// referenceGrant builds a ReferenceGrant in toNamespace that authorizes
+// PersistentVolumeClaims in fromNamespace to reference objects of toKind
+// (and, if toName is non-nil, only the object named *toName).
+func referenceGrant(fromNamespace, toKind, toNamespace string, toName *string) *gatewayapi.ReferenceGrant {
+	var name *gatewayapi.ObjectName
+	if toName != nil {
+		name = (*gatewayapi.ObjectName)(toName)
+	}
+	return &gatewayapi.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "grant",
+			Namespace: toNamespace,
+		},
+		Spec: gatewayapi.ReferenceGrantSpec{
+			From: []gatewayapi.ReferenceGrantFrom{
+				{
+					Group:     gatewayapi.Group(""),
+					Kind:      gatewayapi.Kind("PersistentVolumeClaim"),
+					Namespace: gatewayapi.Namespace(fromNamespace),
+				},
+			},
+			To: []gatewayapi.ReferenceGrantTo{
+				{
+					Group: gatewayapi.Group(""),
+					Kind:  gatewayapi.Kind(toKind),
+					Name:  name,
+				},
+			},
+		},
+	}
+}
+
+func newTestController(objs ...interface{}) (*ephemeralController, *fake.Clientset) {
+	client := fake.NewSimpleClientset()
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	gatewayInformerFactory := gatewayinformers.NewSharedInformerFactory(gatewayfake.NewSimpleClientset(), 0)
+
+	ctrl, err := NewController(client, informerFactory.Core().V1().Pods(), informerFactory.Core().V1().PersistentVolumeClaims(), gatewayInformerFactory.Gateway().V1beta1().ReferenceGrants(), informerFactory.Storage().V1().StorageClasses())
+	if err != nil {
+		panic(err)
+	}
+	ec := ctrl.(*ephemeralController)
+
+	for _, obj := range objs {
+		switch o := obj.(type) {
+		case *v1.Pod:
+			if err := informerFactory.Core().V1().Pods().Informer().GetIndexer().Add(o); err != nil {
+				panic(err)
+			}
+		case *v1.PersistentVolumeClaim:
+			if err := informerFactory.Core().V1().PersistentVolumeClaims().Informer().GetIndexer().Add(o); err != nil {
+				panic(err)
+			}
+		case *storagev1.StorageClass:
+			if err := informerFactory.Storage().V1().StorageClasses().Informer().GetIndexer().Add(o); err != nil {
+				panic(err)
+			}
+		case *gatewayapi.ReferenceGrant:
+			if err := gatewayInformerFactory.Gateway().V1beta1().ReferenceGrants().Informer().GetIndexer().Add(o); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	return ec, client
+}
+
+// TestHandleVolumeRetainOmitsOwnerReference verifies that a PVC created for
+// a volume requesting RetentionPolicyRetain has no owner reference to the
+// pod, so pod deletion does not trigger garbage collection of the PVC.
+func TestHandleVolumeRetainOmitsOwnerReference(t *testing.T) {
+	pod := testPod(testPodUID, ephemeralVolume("data", true))
+	ec, client := newTestController()
+
+	if err := ec.handleVolume(context.Background(), pod, pod.Spec.Volumes[0]); err != nil {
+		t.Fatalf("handleVolume: %v", err)
+	}
+
+	pvc, err := client.CoreV1().PersistentVolumeClaims(testNamespace).Get(context.Background(), pod.Name+"-data", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get PVC: %v", err)
+	}
+	if len(pvc.OwnerReferences) != 0 {
+		t.Fatalf("expected no owner references on a retained PVC, got %+v", pvc.OwnerReferences)
+	}
+	if pvc.Annotations[annPodUID] != string(testPodUID) {
+		t.Fatalf("expected pod UID annotation %q, got %q", testPodUID, pvc.Annotations[annPodUID])
+	}
+}
+
+// TestHandleVolumeDeleteOmitsPodUIDAnnotation verifies that a PVC created
+// for the default RetentionPolicyDelete volume gets an owner reference, as
+// before, and does not carry the annPodUID bookkeeping annotation that is
+// only needed for retained PVCs.
+func TestHandleVolumeDeleteOmitsPodUIDAnnotation(t *testing.T) {
+	pod := testPod(testPodUID, ephemeralVolume("data", false))
+	ec, client := newTestController()
+
+	if err := ec.handleVolume(context.Background(), pod, pod.Spec.Volumes[0]); err != nil {
+		t.Fatalf("handleVolume: %v", err)
+	}
+
+	pvc, err := client.CoreV1().PersistentVolumeClaims(testNamespace).Get(context.Background(), pod.Name+"-data", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("get PVC: %v", err)
+	}
+	if len(pvc.OwnerReferences) != 1 {
+		t.Fatalf("expected one owner reference on a Delete-policy PVC, got %+v", pvc.OwnerReferences)
+	}
+	if _, ok := pvc.Annotations[annPodUID]; ok {
+		t.Fatalf("did not expect the annPodUID annotation on a Delete-policy PVC, got %q", pvc.Annotations[annPodUID])
+	}
+}
+
+// TestOnPodDeleteMetrics verifies that pod deletion is accounted for
+// separately depending on the retention policy of each ephemeral volume.
+func TestOnPodDeleteMetrics(t *testing.T) {
+	pod := testPod(testPodUID,
+		ephemeralVolume("retained", true),
+		ephemeralVolume("deleted", false),
+	)
+	ec, _ := newTestController()
+
+	retainedBefore, err := testutil.GetCounterMetricValue(ephemeralvolumemetrics.EphemeralVolumeRetainedOnPodDeletion)
+	if err != nil {
+		t.Fatalf("get retained counter: %v", err)
+	}
+	deletedBefore, err := testutil.GetCounterMetricValue(ephemeralvolumemetrics.EphemeralVolumeDeletedOnPodDeletion)
+	if err != nil {
+		t.Fatalf("get deleted counter: %v", err)
+	}
+
+	ec.onPodDelete(pod)
+
+	retainedAfter, err := testutil.GetCounterMetricValue(ephemeralvolumemetrics.EphemeralVolumeRetainedOnPodDeletion)
+	if err != nil {
+		t.Fatalf("get retained counter: %v", err)
+	}
+	deletedAfter, err := testutil.GetCounterMetricValue(ephemeralvolumemetrics.EphemeralVolumeDeletedOnPodDeletion)
+	if err != nil {
+		t.Fatalf("get deleted counter: %v", err)
+	}
+
+	if got := retainedAfter - retainedBefore; got != 1 {
+		t.Fatalf("expected retained counter to increase by 1, increased by %v", got)
+	}
+	if got := deletedAfter - deletedBefore; got != 1 {
+		t.Fatalf("expected deleted counter to increase by 1, increased by %v", got)
+	}
+}
+
+// TestOnPVCDeleteSkipsRetainedVolumeOfDeletedPod verifies that a retained
+// PVC deleted out-of-band is not recreated once its pod is also gone.
+func TestOnPVCDeleteSkipsRetainedVolumeOfDeletedPod(t *testing.T) {
+	now := metav1.Now()
+	pod := testPod(testPodUID, ephemeralVolume("data", true))
+	pod.DeletionTimestamp = &now
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name + "-data",
+			Namespace: testNamespace,
+			Annotations: map[string]string{
+				AnnRetentionPolicy: string(RetentionPolicyRetain),
+				annPodUID:          string(testPodUID),
+			},
+		},
+	}
+
+	ec, client := newTestController(pod, pvc)
+	ec.onPVCDelete(pvc)
+
+	if actions := client.Actions(); len(actions) != 0 {
+		t.Fatalf("expected no API calls when a retained PVC's pod is also being deleted, got %+v", actions)
+	}
+	if n := ec.queue.Len(); n != 0 {
+		t.Fatalf("expected the deleted pod not to be queued for PVC recreation, queue length is %d", n)
+	}
+}
+
+// TestHandleVolumeControllerRestartIsIdempotent verifies that re-running
+// handleVolume against a PVC that was already created for this pod (as
+// happens after a controller restart) does not attempt to create it again.
+func TestHandleVolumeControllerRestartIsIdempotent(t *testing.T) {
+	pod := testPod(testPodUID, ephemeralVolume("data", true))
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pod.Name + "-data",
+			Namespace: testNamespace,
+			Annotations: map[string]string{
+				AnnRetentionPolicy: string(RetentionPolicyRetain),
+				annPodUID:          string(testPodUID),
+			},
+		},
+	}
+	ec, client := newTestController(pod, pvc)
+
+	if err := ec.handleVolume(context.Background(), pod, pod.Spec.Volumes[0]); err != nil {
+		t.Fatalf("handleVolume: %v", err)
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "create" {
+			t.Fatalf("did not expect a create action, got %+v", action)
+		}
+	}
+}
+
+// TestHandleVolumeRejectsMismatchedPodUID verifies that a pod cannot adopt
+// a retained PVC that was stamped with a different pod's UID, e.g. because
+// a new pod was created reusing the name of a deleted one.
+func TestHandleVolumeRejectsMismatchedPodUID(t *testing.T) {
+	newPod := testPod(types.UID("uid-2"), ephemeralVolume("data", true))
+	pvc := &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      newPod.Name + "-data",
+			Namespace: testNamespace,
+			Annotations: map[string]string{
+				AnnRetentionPolicy: string(RetentionPolicyRetain),
+				annPodUID:          string(testPodUID),
+			},
+		},
+	}
+	ec, _ := newTestController(pvc)
+
+	if err := ec.handleVolume(context.Background(), newPod, newPod.Spec.Volumes[0]); err == nil {
+		t.Fatal("expected an error for a PVC retained by a different pod UID")
+	}
+}
+
+func expandableStorageClass(name string) *storagev1.StorageClass {
+	allowExpansion := true
+	return &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: name},
+		AllowVolumeExpansion: &allowExpansion,
+	}
+}
+
+// pvcWithSize builds a PVC owned by testPodName/testPodUID the way
+// handleVolume creates one for a RetentionPolicyDelete volume, i.e. via an
+// owner reference rather than the annPodUID annotation used only for
+// retained PVCs.
+func pvcWithSize(name, storageClassName, size string) *v1.PersistentVolumeClaim {
+	isTrue := true
+	return &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion:         "v1",
+					Kind:               "Pod",
+					Name:               testPodName,
+					UID:                testPodUID,
+					Controller:         &isTrue,
+					BlockOwnerDeletion: &isTrue,
+				},
+			},
+		},
+		Spec: v1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClassName,
+			Resources: v1.ResourceRequirements{
+				Requests: v1.ResourceList{
+					v1.ResourceStorage: resource.MustParse(size),
+				},
+			},
+		},
+	}
+}
+
+// TestReconcileSizeNoop verifies that a PVC already matching the requested
+// size is left untouched.
+func TestReconcileSizeNoop(t *testing.T) {
+	pod := testPod(testPodUID, ephemeralVolumeWithSize("data", "1Gi"))
+	pvc := pvcWithSize(pod.Name+"-data", "expandable", "1Gi")
+	ec, client := newTestController(pod, pvc, expandableStorageClass("expandable"))
+
+	if err := ec.handleVolume(context.Background(), pod, pod.Spec.Volumes[0]); err != nil {
+		t.Fatalf("handleVolume: %v", err)
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "patch" {
+			t.Fatalf("did not expect a patch action for a matching size, got %+v", action)
+		}
+	}
+}
+
+// TestReconcileSizeGrows verifies that a PVC is grown via PATCH when the
+// template now requests more storage and the StorageClass allows expansion.
+func TestReconcileSizeGrows(t *testing.T) {
+	pod := testPod(testPodUID, ephemeralVolumeWithSize("data", "2Gi"))
+	pvc := pvcWithSize(pod.Name+"-data", "expandable", "1Gi")
+	ec, client := newTestController(pod, pvc, expandableStorageClass("expandable"))
+	// The PATCH goes to the API server, so the PVC must exist there too,
+	// not only in the informer cache.
+	if err := client.Tracker().Add(pvc); err != nil {
+		t.Fatalf("add PVC to fake client: %v", err)
+	}
+
+	if err := ec.handleVolume(context.Background(), pod, pod.Spec.Volumes[0]); err != nil {
+		t.Fatalf("handleVolume: %v", err)
+	}
+
+	found := false
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "patch" && action.GetResource().Resource == "persistentvolumeclaims" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected a patch action growing the PVC")
+	}
+}
+
+// TestReconcileSizeRejectsShrink verifies that a template requesting less
+// storage than the PVC already has is a no-op rather than an error.
+func TestReconcileSizeRejectsShrink(t *testing.T) {
+	pod := testPod(testPodUID, ephemeralVolumeWithSize("data", "1Gi"))
+	pvc := pvcWithSize(pod.Name+"-data", "expandable", "2Gi")
+	ec, client := newTestController(pod, pvc, expandableStorageClass("expandable"))
+
+	if err := ec.handleVolume(context.Background(), pod, pod.Spec.Volumes[0]); err != nil {
+		t.Fatalf("handleVolume: %v", err)
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "patch" {
+			t.Fatalf("did not expect a patch action for a shrink request, got %+v", action)
+		}
+	}
+}
+
+// TestReconcileSizeWithoutExpansionSupport verifies that growth is skipped,
+// without an error, when the StorageClass does not allow expansion.
+func TestReconcileSizeWithoutExpansionSupport(t *testing.T) {
+	pod := testPod(testPodUID, ephemeralVolumeWithSize("data", "2Gi"))
+	pvc := pvcWithSize(pod.Name+"-data", "fixed", "1Gi")
+	fixedSizeClass := &storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fixed"}}
+	ec, client := newTestController(pod, pvc, fixedSizeClass)
+
+	if err := ec.handleVolume(context.Background(), pod, pod.Spec.Volumes[0]); err != nil {
+		t.Fatalf("handleVolume: %v", err)
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "patch" {
+			t.Fatalf("did not expect a patch action without expansion support, got %+v", action)
+		}
+	}
+}
+
+// TestCheckReferenceGrantAllows verifies that a ReferenceGrant naming the
+// PersistentVolumeClaim's own namespace as an allowed "From" authorizes the
+// cross-namespace DataSourceRef.
+func TestCheckReferenceGrantAllows(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.CrossNamespaceVolumeDataSource, true)
+
+	pod := testPod(testPodUID)
+	grant := referenceGrant(testNamespace, "VolumeSnapshot", sourceNamespace, nil)
+	ec, _ := newTestController(grant)
+
+	ref := &v1.TypedObjectReference{Kind: "VolumeSnapshot", Name: "snap", Namespace: ptr.To(sourceNamespace)}
+	if err := ec.checkReferenceGrant(pod, ref); err != nil {
+		t.Fatalf("expected the ReferenceGrant to allow the reference, got %v", err)
+	}
+}
+
+// TestCheckReferenceGrantDeniesWithoutMatch verifies that no ReferenceGrant
+// at all results in a denial.
+func TestCheckReferenceGrantDeniesWithoutMatch(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.CrossNamespaceVolumeDataSource, true)
+
+	pod := testPod(testPodUID)
+	ec, _ := newTestController()
+
+	ref := &v1.TypedObjectReference{Kind: "VolumeSnapshot", Name: "snap", Namespace: ptr.To(sourceNamespace)}
+	if err := ec.checkReferenceGrant(pod, ref); err == nil {
+		t.Fatal("expected an error when no ReferenceGrant matches")
+	}
+}
+
+// TestCheckReferenceGrantDeniesWrongName verifies that a ReferenceGrant
+// naming a different object does not authorize the reference.
+func TestCheckReferenceGrantDeniesWrongName(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.CrossNamespaceVolumeDataSource, true)
+
+	pod := testPod(testPodUID)
+	grant := referenceGrant(testNamespace, "VolumeSnapshot", sourceNamespace, ptr.To("other-snap"))
+	ec, _ := newTestController(grant)
+
+	ref := &v1.TypedObjectReference{Kind: "VolumeSnapshot", Name: "snap", Namespace: ptr.To(sourceNamespace)}
+	if err := ec.checkReferenceGrant(pod, ref); err == nil {
+		t.Fatal("expected an error when the ReferenceGrant names a different object")
+	}
+}
+
+// TestHandleVolumeCrossNamespaceRequiresFeatureGate verifies that a
+// cross-namespace DataSourceRef is rejected outright when
+// CrossNamespaceVolumeDataSource is disabled, without creating a PVC.
+func TestHandleVolumeCrossNamespaceRequiresFeatureGate(t *testing.T) {
+	pod := testPod(testPodUID, ephemeralVolumeWithDataSourceRef("data", sourceNamespace, "VolumeSnapshot", "snap"))
+	ec, client := newTestController()
+
+	if err := ec.handleVolume(context.Background(), pod, pod.Spec.Volumes[0]); err == nil {
+		t.Fatal("expected an error for a cross-namespace DataSourceRef without the feature gate enabled")
+	}
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "create" {
+			t.Fatalf("did not expect a create action, got %+v", action)
+		}
+	}
+}
+
+// TestHandleVolumeCrossNamespaceAllowedMetrics verifies that an authorized
+// cross-namespace DataSourceRef is counted as an attempt but not a denial.
+func TestHandleVolumeCrossNamespaceAllowedMetrics(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.CrossNamespaceVolumeDataSource, true)
+
+	pod := testPod(testPodUID, ephemeralVolumeWithDataSourceRef("data", sourceNamespace, "VolumeSnapshot", "snap"))
+	grant := referenceGrant(testNamespace, "VolumeSnapshot", sourceNamespace, nil)
+	ec, _ := newTestController(grant)
+
+	attemptsBefore, err := testutil.GetCounterMetricValue(ephemeralvolumemetrics.EphemeralVolumeCrossNamespaceAttempts)
+	if err != nil {
+		t.Fatalf("get attempts counter: %v", err)
+	}
+	deniedBefore, err := testutil.GetCounterMetricValue(ephemeralvolumemetrics.EphemeralVolumeCrossNamespaceDenied)
+	if err != nil {
+		t.Fatalf("get denied counter: %v", err)
+	}
+
+	if err := ec.handleVolume(context.Background(), pod, pod.Spec.Volumes[0]); err != nil {
+		t.Fatalf("handleVolume: %v", err)
+	}
+
+	attemptsAfter, err := testutil.GetCounterMetricValue(ephemeralvolumemetrics.EphemeralVolumeCrossNamespaceAttempts)
+	if err != nil {
+		t.Fatalf("get attempts counter: %v", err)
+	}
+	deniedAfter, err := testutil.GetCounterMetricValue(ephemeralvolumemetrics.EphemeralVolumeCrossNamespaceDenied)
+	if err != nil {
+		t.Fatalf("get denied counter: %v", err)
+	}
+
+	if got := attemptsAfter - attemptsBefore; got != 1 {
+		t.Fatalf("expected attempts counter to increase by 1, increased by %v", got)
+	}
+	if got := deniedAfter - deniedBefore; got != 0 {
+		t.Fatalf("expected denied counter to stay unchanged, increased by %v", got)
+	}
+}
+
+// TestHandleVolumeCrossNamespaceDeniedMetrics verifies that a
+// cross-namespace DataSourceRef without a matching ReferenceGrant is
+// counted as a denial and surfaces a missingReferenceGrantError.
+func TestHandleVolumeCrossNamespaceDeniedMetrics(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.CrossNamespaceVolumeDataSource, true)
+
+	pod := testPod(testPodUID, ephemeralVolumeWithDataSourceRef("data", sourceNamespace, "VolumeSnapshot", "snap"))
+	ec, _ := newTestController()
+
+	deniedBefore, err := testutil.GetCounterMetricValue(ephemeralvolumemetrics.EphemeralVolumeCrossNamespaceDenied)
+	if err != nil {
+		t.Fatalf("get denied counter: %v", err)
+	}
+
+	err = ec.handleVolume(context.Background(), pod, pod.Spec.Volumes[0])
+	if err == nil {
+		t.Fatal("expected an error when no ReferenceGrant allows the reference")
+	}
+	var missingGrant *missingReferenceGrantError
+	if !goerrors.As(err, &missingGrant) {
+		t.Fatalf("expected a missingReferenceGrantError, got %T: %v", err, err)
+	}
+
+	deniedAfter, err := testutil.GetCounterMetricValue(ephemeralvolumemetrics.EphemeralVolumeCrossNamespaceDenied)
+	if err != nil {
+		t.Fatalf("get denied counter: %v", err)
+	}
+	if got := deniedAfter - deniedBefore; got != 1 {
+		t.Fatalf("expected denied counter to increase by 1, increased by %v", got)
+	}
+}
+
+// TestSyncHandlerEmitsMissingReferenceGrantEvent verifies that syncHandler
+// records a FailedBinding event with reason MissingReferenceGrant, rather
+// than the generic FailedBinding reason, when a cross-namespace
+// DataSourceRef cannot be authorized.
+func TestSyncHandlerEmitsMissingReferenceGrantEvent(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, utilfeature.DefaultFeatureGate, features.CrossNamespaceVolumeDataSource, true)
+
+	pod := testPod(testPodUID, ephemeralVolumeWithDataSourceRef("data", sourceNamespace, "VolumeSnapshot", "snap"))
+	ec, _ := newTestController(pod)
+	fakeRecorder := record.NewFakeRecorder(10)
+	ec.recorder = fakeRecorder
+
+	key, err := cache.MetaNamespaceKeyFunc(pod)
+	if err != nil {
+		t.Fatalf("get key for pod: %v", err)
+	}
+
+	if err := ec.syncHandler(context.Background(), key); err == nil {
+		t.Fatal("expected an error when no ReferenceGrant allows the reference")
+	}
+
+	select {
+	case event := <-fakeRecorder.Events:
+		if !strings.Contains(event, events.MissingReferenceGrant) {
+			t.Fatalf("expected event reason %s, got %q", events.MissingReferenceGrant, event)
+		}
+	default:
+		t.Fatal("expected syncHandler to record an event")
+	}
+}
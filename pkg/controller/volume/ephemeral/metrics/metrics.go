@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics contains all the prometheus metrics for the ephemeral
+// volume controller.
+package metrics
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+const ephemeralVolumeSubsystem = "ephemeral_volume_controller"
+
+var (
+	// EphemeralVolumeCreateAttempts tracks the number of
+	// PersistentVolumeClaims creation requests.
+	EphemeralVolumeCreateAttempts = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      ephemeralVolumeSubsystem,
+			Name:           "create_total",
+			Help:           "Number of PersistentVolumeClaims creation requests",
+			StabilityLevel: metrics.ALPHA,
+		})
+	// EphemeralVolumeCreateFailures tracks the number of
+	// PersistentVolumeClaims creation requests that failed.
+	EphemeralVolumeCreateFailures = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      ephemeralVolumeSubsystem,
+			Name:           "create_failures_total",
+			Help:           "Number of PersistentVolumeClaims creation requests that failed",
+			StabilityLevel: metrics.ALPHA,
+		})
+	// EphemeralVolumeCrossNamespaceAttempts tracks the number of ephemeral
+	// volumes whose DataSourceRef points at a different namespace than the
+	// pod, regardless of whether the reference was ultimately allowed.
+	EphemeralVolumeCrossNamespaceAttempts = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      ephemeralVolumeSubsystem,
+			Name:           "cross_namespace_total",
+			Help:           "Number of ephemeral volumes with a cross-namespace DataSourceRef",
+			StabilityLevel: metrics.ALPHA,
+		})
+	// EphemeralVolumeCrossNamespaceDenied tracks the number of
+	// cross-namespace DataSourceRef attempts that were rejected because no
+	// matching ReferenceGrant was found in the target namespace.
+	EphemeralVolumeCrossNamespaceDenied = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      ephemeralVolumeSubsystem,
+			Name:           "cross_namespace_denied_total",
+			Help:           "Number of cross-namespace DataSourceRef attempts denied because no ReferenceGrant allowed them",
+			StabilityLevel: metrics.ALPHA,
+		})
+	// EphemeralVolumeRetainedOnPodDeletion tracks the number of ephemeral
+	// PVCs left behind on pod deletion because of a Retain policy.
+	EphemeralVolumeRetainedOnPodDeletion = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      ephemeralVolumeSubsystem,
+			Name:           "retained_total",
+			Help:           "Number of ephemeral PVCs retained instead of deleted when their pod was deleted",
+			StabilityLevel: metrics.ALPHA,
+		})
+	// EphemeralVolumeDeletedOnPodDeletion tracks the number of ephemeral
+	// PVCs that were garbage collected along with their pod because of a
+	// Delete policy.
+	EphemeralVolumeDeletedOnPodDeletion = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      ephemeralVolumeSubsystem,
+			Name:           "deleted_total",
+			Help:           "Number of ephemeral PVCs deleted along with their pod",
+			StabilityLevel: metrics.ALPHA,
+		})
+	// EphemeralVolumeExpansionAttempts tracks the number of PATCH
+	// requests issued to grow an existing ephemeral PVC.
+	EphemeralVolumeExpansionAttempts = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      ephemeralVolumeSubsystem,
+			Name:           "expand_total",
+			Help:           "Number of requests to expand an existing ephemeral PVC",
+			StabilityLevel: metrics.ALPHA,
+		})
+	// EphemeralVolumeExpansionFailures tracks the number of PVC expansion
+	// requests that failed.
+	EphemeralVolumeExpansionFailures = metrics.NewCounter(
+		&metrics.CounterOpts{
+			Subsystem:      ephemeralVolumeSubsystem,
+			Name:           "expand_failures_total",
+			Help:           "Number of ephemeral PVC expansion requests that failed",
+			StabilityLevel: metrics.ALPHA,
+		})
+)
+
+var registerMetrics sync.Once
+
+// RegisterMetrics registers the ephemeral volume controller metrics.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(EphemeralVolumeCreateAttempts)
+		legacyregistry.MustRegister(EphemeralVolumeCreateFailures)
+		legacyregistry.MustRegister(EphemeralVolumeCrossNamespaceAttempts)
+		legacyregistry.MustRegister(EphemeralVolumeCrossNamespaceDenied)
+		legacyregistry.MustRegister(EphemeralVolumeRetainedOnPodDeletion)
+		legacyregistry.MustRegister(EphemeralVolumeDeletedOnPodDeletion)
+		legacyregistry.MustRegister(EphemeralVolumeExpansionAttempts)
+		legacyregistry.MustRegister(EphemeralVolumeExpansionFailures)
+	})
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2017 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package events contains the event reason constants shared by the volume
+// controllers.
+package events
+
+const (
+	// FailedBinding is the event reason for marking failed volume provisioning.
+	FailedBinding = "FailedBinding"
+
+	// MissingReferenceGrant is the event reason for a FailedBinding event
+	// raised when a cross-namespace DataSourceRef could not be authorized
+	// because no matching ReferenceGrant was found in the target namespace.
+	MissingReferenceGrant = "MissingReferenceGrant"
+)
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package json provides a klog logr.Logger and an io.Writer that both emit
+// one JSON object per line, for use by the logs package when
+// --logging-format=json is selected.
+package json
+
+import (
+	"io"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewJSONLogger creates a logr.Logger that writes one JSON object per line
+// to w, with "ts", "level", "msg" and structured key/value fields, as well
+// as a Writer that produces the same JSON shape for output that isn't
+// already going through the structured logging APIs (e.g. the redirected
+// standard library log package).
+func NewJSONLogger(w zapcore.WriteSyncer) (logr.Logger, io.Writer) {
+	encoderConfig := zapcore.EncoderConfig{
+		MessageKey:     "msg",
+		LevelKey:       "level",
+		TimeKey:        "ts",
+		NameKey:        "logger",
+		CallerKey:      "caller",
+		StacktraceKey:  "stacktrace",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.LowercaseLevelEncoder,
+		EncodeTime:     zapcore.EpochTimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+	// klog maps its own verbosity levels (V(1), V(2), ...) to negative zap
+	// levels through zapr, and already decides itself, based on --v,
+	// whether a given V(n) call should reach the logr.Logger at all. The
+	// level enabler here must therefore not re-filter on top of that
+	// decision: InfoLevel would silently drop every V(n>0) call
+	// regardless of --v. minLevel lets everything klog forwards through.
+	const minLevel = zapcore.Level(-128)
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), w, minLevel)
+	zapLogger := zap.New(core)
+	return zapr.NewLogger(zapLogger), &writer{core: core}
+}
+
+// writer implements io.Writer on top of a zapcore.Core, for output (such as
+// the redirected standard library log package) that doesn't go through
+// logr but should still be shaped like the rest of the JSON log stream.
+type writer struct {
+	core zapcore.Core
+}
+
+func (w *writer) Write(data []byte) (int, error) {
+	msg := string(data)
+	if n := len(msg); n > 0 && msg[n-1] == '\n' {
+		msg = msg[:n-1]
+	}
+	entry := zapcore.Entry{
+		Level:   zapcore.InfoLevel,
+		Message: msg,
+	}
+	if err := w.core.Write(entry, nil); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
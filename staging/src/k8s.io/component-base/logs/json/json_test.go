@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// buffer adapts a bytes.Buffer to zapcore.WriteSyncer for use in tests.
+type buffer struct {
+	bytes.Buffer
+}
+
+func (b *buffer) Sync() error { return nil }
+
+func TestNewJSONLoggerSchema(t *testing.T) {
+	var buf buffer
+	logger, _ := NewJSONLogger(&buf)
+
+	logger.Info("hello world", "foo", "bar")
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected valid JSON, got error %v for line %q", err, buf.String())
+	}
+
+	for _, key := range []string{"ts", "level", "msg"} {
+		if _, ok := entry[key]; !ok {
+			t.Errorf("expected key %q in log entry, got %v", key, entry)
+		}
+	}
+	if msg, _ := entry["msg"].(string); msg != "hello world" {
+		t.Errorf("expected msg %q, got %q", "hello world", msg)
+	}
+	if foo, _ := entry["foo"].(string); foo != "bar" {
+		t.Errorf("expected foo %q, got %q", "bar", foo)
+	}
+}
+
+func TestJSONWriterSchema(t *testing.T) {
+	var buf buffer
+	_, writer := NewJSONLogger(&buf)
+
+	if _, err := writer.Write([]byte("plain text message\n")); err != nil {
+		t.Fatalf("unexpected error writing: %v", err)
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected valid JSON, got error %v for line %q", err, buf.String())
+	}
+	if msg, _ := entry["msg"].(string); msg != "plain text message" {
+		t.Errorf("expected msg %q, got %q", "plain text message", msg)
+	}
+	if _, ok := entry["ts"]; !ok {
+		t.Errorf("expected key %q in log entry, got %v", "ts", entry)
+	}
+}
+
+var _ zapcore.WriteSyncer = &buffer{}
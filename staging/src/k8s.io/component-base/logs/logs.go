@@ -23,14 +23,21 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/pflag"
+	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
+
+	"k8s.io/component-base/logs/json"
 )
 
 const logFlushFreqFlagName = "log-flush-frequency"
+const loggingFormatFlagName = "logging-format"
 const deprecated = "will be removed in a future release, see https://github.com/kubernetes/enhancements/tree/master/keps/sig-instrumentation/2845-deprecate-klog-specific-flags-in-k8s-components"
 
 // TODO (https://github.com/kubernetes/kubernetes/issues/105310): once klog
@@ -39,9 +46,22 @@ const deprecated = "will be removed in a future release, see https://github.com/
 //
 // const vmoduleUsage = " (only works for the default text log format)"
 
+const (
+	// LogFormatText is the traditional klog text output. It is the default.
+	LogFormatText = "text"
+	// LogFormatJSON emits one JSON object per line instead.
+	LogFormatJSON = "json"
+)
+
+// klogSpecificFlags are ignored once a non-default --logging-format is
+// selected, because they only affect klog's own text formatting.
+var klogSpecificFlags = []string{"log-dir", "log-file", "log-file-max-size", "logtostderr", "alsologtostderr", "one-output", "skip-headers", "skip-log-headers", "add-dir-header", "vmodule", "log-backtrace-at", "stderrthreshold"}
+
 var (
 	packageFlags = flag.NewFlagSet("logging", flag.ContinueOnError)
 	logFlushFreq time.Duration
+	logFormat    = LogFormatText
+	warnOnce     sync.Once
 )
 
 func init() {
@@ -49,6 +69,27 @@ func init() {
 	packageFlags.DurationVar(&logFlushFreq, logFlushFreqFlagName, 5*time.Second, "Maximum number of seconds between log flushes")
 }
 
+// logFormatFlag implements pflag.Value (and flag.Value) and rejects any
+// value other than LogFormatText or LogFormatJSON.
+type logFormatFlag struct{}
+
+func (logFormatFlag) String() string { return logFormat }
+
+func (logFormatFlag) Set(value string) error {
+	switch value {
+	case LogFormatText, LogFormatJSON:
+		logFormat = value
+		return nil
+	default:
+		return fmt.Errorf("unsupported log format %q, must be one of: %s, %s", value, LogFormatText, LogFormatJSON)
+	}
+}
+
+func (logFormatFlag) Type() string { return "string" }
+
+const loggingFormatUsage = "Sets the log format. Permitted formats: \"text\" (default), \"json\". " +
+	"Non-default formats don't honor these flags: --add_dir_header, --alsologtostderr, --log_backtrace_at, --log_dir, --log_file, --log_file_max_size, --logtostderr, --one_output, --skip_headers, --skip_log_headers, --stderrthreshold, --vmodule. This flag is alpha and may change in the future."
+
 // AddFlags registers this package's flags on arbitrary FlagSets. This includes
 // the klog flags, with the original underscore as separator between. If
 // commands want hyphens as separators, they can set
@@ -78,6 +119,8 @@ func AddFlags(fs *pflag.FlagSet) {
 		}
 		fs.AddFlag(pf)
 	})
+
+	fs.Var(logFormatFlag{}, loggingFormatFlagName, loggingFormatUsage)
 }
 
 // AddGoFlags is a variant of AddFlags for traditional Go flag.FlagSet.
@@ -102,6 +145,8 @@ func AddGoFlags(fs *flag.FlagSet) {
 		}
 		fs.Var(f.Value, f.Name, usage)
 	})
+
+	fs.Var(logFormatFlag{}, loggingFormatFlagName, loggingFormatUsage)
 }
 
 // KlogWriter serves as a bridge between the standard log package and the glog package.
@@ -117,12 +162,51 @@ func (writer KlogWriter) Write(data []byte) (n int, err error) {
 // It should be called after parsing flags. If called before that,
 // it will use the default log settings.
 func InitLogs() {
-	log.SetOutput(KlogWriter{})
+	if logFormat == LogFormatJSON {
+		logger, jsonWriter := json.NewJSONLogger(zapcore.Lock(zapcore.AddSync(os.Stderr)))
+		klog.SetLogger(logger)
+		log.SetOutput(jsonWriter)
+		// Only warn once klog itself is writing JSON: otherwise this
+		// message would print as a plain klog text line despite
+		// --logging-format=json having just been selected.
+		warnOnNonDefaultFormat()
+	} else {
+		log.SetOutput(KlogWriter{})
+	}
 	log.SetFlags(0)
 	// The default klog flush interval is 5 seconds.
 	go wait.Forever(klog.Flush, logFlushFreq)
 }
 
+// warnOnNonDefaultFormat prints, once, a warning that the klog-specific
+// flags in klogSpecificFlags have no effect with the selected format.
+func warnOnNonDefaultFormat() {
+	warnOnce.Do(func() {
+		klog.InfoS("Non-default format requested, klog-specific flags are ignored", "format", logFormat, "ignoredFlags", klogSpecificFlags)
+	})
+}
+
+// ValidateLoggingConfiguration checks the flags registered by AddFlags for
+// inconsistencies. In particular it rejects explicitly set klog-specific
+// flags (log-file, vmodule, alsologtostderr, etc.) when a non-default
+// --logging-format was also requested, because those flags have no effect
+// outside of the text format.
+func ValidateLoggingConfiguration(fs *pflag.FlagSet) error {
+	if logFormat == LogFormatText {
+		return nil
+	}
+	var changed []string
+	for _, name := range klogSpecificFlags {
+		if f := fs.Lookup(name); f != nil && f.Changed {
+			changed = append(changed, name)
+		}
+	}
+	if len(changed) > 0 {
+		return fmt.Errorf("--%s=%s is incompatible with explicitly set flag(s): %s", loggingFormatFlagName, logFormat, strings.Join(changed, ", "))
+	}
+	return nil
+}
+
 // FlushLogs flushes logs immediately. This should be called at the end of
 // the main function via defer to ensure that all pending log messages
 // are printed before exiting the program.
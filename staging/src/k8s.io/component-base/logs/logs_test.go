@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+	cliflag "k8s.io/component-base/cli/flag"
+)
+
+// newTestFlagSet builds a FlagSet the way kube-controller-manager and
+// friends do: with hyphens normalized the same as underscores, since that's
+// what makes the hyphenated names in klogSpecificFlags resolve to the
+// underscore-named klog flags.
+func newTestFlagSet(t *testing.T) *pflag.FlagSet {
+	t.Helper()
+	logFormat = LogFormatText
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	fs.SetNormalizeFunc(cliflag.WordSepNormalizeFunc)
+	AddFlags(fs)
+	return fs
+}
+
+func TestValidateLoggingConfigurationDefaultFormat(t *testing.T) {
+	fs := newTestFlagSet(t)
+	if err := fs.Set("vmodule", "foo=1"); err != nil {
+		t.Fatalf("unexpected error setting vmodule: %v", err)
+	}
+	if err := ValidateLoggingConfiguration(fs); err != nil {
+		t.Errorf("expected no error for default format, got %v", err)
+	}
+}
+
+func TestValidateLoggingConfigurationJSONWithKlogFlag(t *testing.T) {
+	for _, name := range klogSpecificFlags {
+		t.Run(name, func(t *testing.T) {
+			fs := newTestFlagSet(t)
+			if err := fs.Set(loggingFormatFlagName, LogFormatJSON); err != nil {
+				t.Fatalf("unexpected error setting %s: %v", loggingFormatFlagName, err)
+			}
+			value := "1"
+			switch name {
+			case "vmodule":
+				value = "foo=1"
+			case "log-backtrace-at":
+				value = "foo.go:1"
+			}
+			if err := fs.Set(name, value); err != nil {
+				t.Fatalf("unexpected error setting %s: %v", name, err)
+			}
+			if err := ValidateLoggingConfiguration(fs); err == nil {
+				t.Errorf("expected an error when --%s is combined with --logging-format=json, got nil", name)
+			}
+		})
+	}
+}
+
+func TestValidateLoggingConfigurationJSONWithoutKlogFlags(t *testing.T) {
+	fs := newTestFlagSet(t)
+	if err := fs.Set(loggingFormatFlagName, LogFormatJSON); err != nil {
+		t.Fatalf("unexpected error setting %s: %v", loggingFormatFlagName, err)
+	}
+	if err := ValidateLoggingConfiguration(fs); err != nil {
+		t.Errorf("expected no error when no klog-specific flags are set, got %v", err)
+	}
+}
+
+func TestLoggingFormatFlagRejectsUnknownValue(t *testing.T) {
+	fs := newTestFlagSet(t)
+	if err := fs.Set(loggingFormatFlagName, "yaml"); err == nil {
+		t.Error("expected an error for an unsupported --logging-format value, got nil")
+	}
+}